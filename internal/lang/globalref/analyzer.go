@@ -0,0 +1,119 @@
+package globalref
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+)
+
+// Analyzer answers questions about how the objects in a configuration
+// relate to one another, using a ReferenceGraph built from the
+// configuration's static references.
+//
+// Construct an Analyzer with NewAnalyzer. An Analyzer is read-only once
+// constructed and so is safe to use concurrently from multiple goroutines,
+// which allows it to be shared by, for example, multiple concurrent
+// "terraform console" requests against the same configuration.
+type Analyzer struct {
+	config *configs.Config
+
+	// graph and graphOnce together make ReferenceGraph build the graph
+	// lazily, on whichever goroutine first asks for it, while still keeping
+	// that safe for the concurrent use promised above.
+	graphOnce sync.Once
+	graph     *ReferenceGraph
+}
+
+// NewAnalyzer constructs a new Analyzer for the given configuration.
+func NewAnalyzer(config *configs.Config) *Analyzer {
+	return &Analyzer{config: config}
+}
+
+// ReferenceGraph returns the directed graph of references between the
+// resources, data sources, module input variables, local values, and
+// outputs in the receiving Analyzer's configuration, building it on the
+// first call and reusing it on subsequent calls.
+func (a *Analyzer) ReferenceGraph() *ReferenceGraph {
+	a.graphOnce.Do(func() {
+		a.graph = buildReferenceGraph(a.config)
+	})
+	return a.graph
+}
+
+// ContributingResources returns the set of resources whose values could
+// contribute, whether directly or indirectly through other resources,
+// module variables, local values, or outputs, to any of the given
+// referenceable addresses.
+//
+// The given addresses are interpreted as belonging to the root module. The
+// result is approximate in the sense described in this package's overview
+// comment: it can include resources that turn out not to matter for a
+// specific value at apply time, and it omits only those that contribute
+// through configuration the analysis does not read.
+func (a *Analyzer) ContributingResources(refs ...addrs.Referenceable) []addrs.AbsResource {
+	return a.relatedResources(refs, a.ReferenceGraph().contributors, func(edge Reference) (addrs.Module, addrs.Referenceable) {
+		return edge.ReferentModule, edge.Referent
+	})
+}
+
+// DependentResources returns the set of resources whose values could be
+// derived, whether directly or indirectly, from any of the given
+// referenceable addresses. This is the mirror image of
+// ContributingResources, and is the primary building block for impact
+// analysis: "what would be affected if this attribute changes?"
+func (a *Analyzer) DependentResources(refs ...addrs.Referenceable) []addrs.AbsResource {
+	return a.relatedResources(refs, a.ReferenceGraph().dependents, func(edge Reference) (addrs.Module, addrs.Referenceable) {
+		return edge.ReferrerModule, edge.Referrer
+	})
+}
+
+// relatedResources collects, from each edge that walk returns, only the end
+// that far picks, so that a queried resource is not counted among its own
+// contributors or dependents unless a cycle leads back to it.
+func (a *Analyzer) relatedResources(refs []addrs.Referenceable, walk func(addrs.Module, addrs.Referenceable) []Reference, far func(Reference) (addrs.Module, addrs.Referenceable)) []addrs.AbsResource {
+	seen := make(map[string]addrs.AbsResource)
+	for _, ref := range refs {
+		for _, edge := range walk(addrs.RootModule, ref) {
+			module, addr := far(edge)
+			addResourceFromReferenceable(seen, module, addr)
+		}
+	}
+
+	ret := make([]addrs.AbsResource, 0, len(seen))
+	for _, res := range seen {
+		ret = append(ret, res)
+	}
+	return ret
+}
+
+// addResourceFromReferenceable records addr in seen if it refers to a
+// resource (or a single instance of one), expressed as an addrs.AbsResource
+// in the given module.
+//
+// NOTE: Because this analysis is based only on the static configuration, it
+// cannot know how many instances a module with count or for_each will have,
+// so it conservatively assumes the module is only ever instantiated once.
+// Callers that also have a plan or state available can use that to expand
+// this into concrete module instances if they need to.
+func addResourceFromReferenceable(seen map[string]addrs.AbsResource, module addrs.Module, addr addrs.Referenceable) {
+	var res addrs.Resource
+	switch s := addr.(type) {
+	case addrs.Resource:
+		res = s
+	case addrs.ResourceInstance:
+		res = s.Resource
+	default:
+		return
+	}
+	absRes := res.Absolute(module.UnkeyedInstanceShim())
+	seen[absRes.String()] = absRes
+}
+
+// ReferencesFromOutputValue returns the set of direct and indirect
+// references that contribute to the value of the given output value,
+// transparently walking through any local values and input variables its
+// expression refers to.
+func (a *Analyzer) ReferencesFromOutputValue(addr addrs.AbsOutputValue) []Reference {
+	return a.ReferenceGraph().contributors(addr.Module.Module(), addr.OutputValue)
+}
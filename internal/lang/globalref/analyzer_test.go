@@ -0,0 +1,52 @@
+package globalref
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// newTestAnalyzer builds an Analyzer around a pre-built graph, without
+// going through buildReferenceGraph, so that ContributingResources and
+// DependentResources can be tested without needing a real *configs.Config.
+func newTestAnalyzer(graph *ReferenceGraph) *Analyzer {
+	a := &Analyzer{}
+	a.graphOnce.Do(func() {}) // mark as already "built"
+	a.graph = graph
+	return a
+}
+
+func TestAnalyzerContributingAndDependentResources(t *testing.T) {
+	root := addrs.RootModule
+	src := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "src"}
+	dst := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "dst"}
+	out := addrs.OutputValue{Name: "out"}
+
+	graph := newReferenceGraph([]Reference{
+		{Referrer: out, ReferrerModule: root, Referent: src, ReferentModule: root},
+		{Referrer: dst, ReferrerModule: root, Referent: out, ReferentModule: root},
+	})
+	analyzer := newTestAnalyzer(graph)
+
+	wantSrc := src.Absolute(root.UnkeyedInstanceShim())
+	wantDst := dst.Absolute(root.UnkeyedInstanceShim())
+
+	contributing := analyzer.ContributingResources(out)
+	if !containsAbsResource(contributing, wantSrc) {
+		t.Errorf("expected ContributingResources(%s) to include %s, got %#v", out, wantSrc, contributing)
+	}
+
+	dependents := analyzer.DependentResources(src)
+	if !containsAbsResource(dependents, wantDst) {
+		t.Errorf("expected DependentResources(%s) to include %s, got %#v", src, wantDst, dependents)
+	}
+}
+
+func containsAbsResource(resources []addrs.AbsResource, want addrs.AbsResource) bool {
+	for _, r := range resources {
+		if r.String() == want.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+// Package globalref deals with the problem of relating the various objects
+// in a configuration to one another across module boundaries.
+//
+// The Analyzer in this package builds a ReferenceGraph from the static
+// references present in a *configs.Config: count/for_each expressions,
+// resource arguments, local values, output values, and module call
+// arguments. Callers can then ask questions like "which resources
+// contribute to this output value?" or "which resources would be affected
+// if this attribute changes?" without needing to re-implement graph
+// traversal themselves.
+//
+// This analysis is currently static only: it doesn't look at a plan's
+// proposed changes, so it can't see data-flow relationships that exist only
+// dynamically (for example, a value that propagates through a provider
+// rather than through configuration). Incorporating that is a natural
+// future extension of this package, but isn't implemented yet.
+//
+// The analysis here is necessarily approximate: answering these questions
+// exactly would require fully evaluating expressions, which can have
+// side-effects (via functions) and can fail outright on invalid input.
+// Instead we only look at the static shape of references, so the results
+// can occasionally be broader than what would actually happen at apply
+// time. Resource and module call bodies are read only when written in the
+// native HCL syntax; references in bodies written in JSON syntax are not
+// found, so relationships that exist only through them are omitted.
+package globalref
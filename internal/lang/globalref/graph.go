@@ -0,0 +1,182 @@
+package globalref
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/lang"
+)
+
+// buildReferenceGraph walks the given configuration, and recursively all of
+// its descendent modules, collecting the static references present in
+// resource (including data source) arguments, local values, output values,
+// and module call arguments.
+//
+// This only considers the shape of the configuration itself. Where a plan's
+// proposed changes are available they can reveal additional data-flow edges
+// that aren't visible here (for example, an unknown value that propagated
+// through a provider rather than through configuration), but modelling that
+// is left for a future iteration of this analyzer.
+func buildReferenceGraph(config *configs.Config) *ReferenceGraph {
+	var edges []Reference
+	var walk func(c *configs.Config)
+	walk = func(c *configs.Config) {
+		if c == nil {
+			return
+		}
+		edges = append(edges, moduleReferences(c.Path, c.Module, c.Children)...)
+		for _, child := range c.Children {
+			walk(child)
+		}
+	}
+	walk(config)
+	return newReferenceGraph(edges)
+}
+
+// moduleReferences returns the Reference edges whose Referrer belongs to the
+// given module, translating any references that cross a module boundary
+// (module call arguments, module call count/for_each, and module call
+// outputs) into their corresponding ReferentModule. children is the given
+// module's own Children, used to look up which input variables a module
+// call's count/for_each expression should be considered to gate.
+func moduleReferences(modulePath addrs.Module, module *configs.Module, children map[string]*configs.Config) []Reference {
+	var edges []Reference
+
+	addEdgesFrom := func(referrer addrs.Referenceable, referrerModule addrs.Module, refs []*addrs.Reference) {
+		for _, ref := range refs {
+			referentModule, referent := crossModuleReferent(modulePath, ref.Subject)
+			edges = append(edges, Reference{
+				Referrer:       referrer,
+				ReferrerModule: referrerModule,
+				Referent:       referent,
+				ReferentModule: referentModule,
+			})
+		}
+	}
+
+	for _, r := range module.ManagedResources {
+		addEdgesFrom(resourceAddr(r), modulePath, resourceExprRefs(r))
+	}
+	for _, r := range module.DataResources {
+		addEdgesFrom(resourceAddr(r), modulePath, resourceExprRefs(r))
+	}
+	for _, l := range module.Locals {
+		addEdgesFrom(addrs.LocalValue{Name: l.Name}, modulePath, lang.ReferencesInExpr(l.Expr))
+	}
+	for _, o := range module.Outputs {
+		addEdgesFrom(addrs.OutputValue{Name: o.Name}, modulePath, lang.ReferencesInExpr(o.Expr))
+	}
+	for _, mc := range module.ModuleCalls {
+		childPath := modulePath.Child(mc.Name)
+		for varName, refs := range moduleCallArgRefs(mc) {
+			addEdgesFrom(addrs.InputVariable{Name: varName}, childPath, refs)
+		}
+
+		// A module's count/for_each expression decides whether (and how
+		// many times) the whole module exists, so whatever it refers to
+		// effectively contributes to every input variable the module
+		// declares, not just the ones the call happens to set explicitly.
+		if gateRefs := moduleCallGateRefs(mc); len(gateRefs) > 0 {
+			if child, ok := children[mc.Name]; ok && child != nil {
+				for varName := range child.Module.Variables {
+					addEdgesFrom(addrs.InputVariable{Name: varName}, childPath, gateRefs)
+				}
+			}
+		}
+	}
+
+	return edges
+}
+
+// moduleCallGateRefs collects the references in a module call's count and
+// for_each expressions, which together decide whether and how many
+// instances of the called module exist.
+func moduleCallGateRefs(mc *configs.ModuleCall) []*addrs.Reference {
+	var refs []*addrs.Reference
+	if mc.Count != nil {
+		refs = append(refs, lang.ReferencesInExpr(mc.Count)...)
+	}
+	if mc.ForEach != nil {
+		refs = append(refs, lang.ReferencesInExpr(mc.ForEach)...)
+	}
+	return refs
+}
+
+// resourceAddr returns the addrs.Resource identifying the given resource
+// configuration block, irrespective of how many instances it may expand to.
+func resourceAddr(r *configs.Resource) addrs.Resource {
+	return addrs.Resource{
+		Mode: r.Mode,
+		Type: r.Type,
+		Name: r.Name,
+	}
+}
+
+// resourceExprRefs collects the references from a resource's count/for_each
+// expressions plus the references in the body of its configuration, which
+// covers both top-level arguments and any references nested inside its
+// nested configuration blocks.
+func resourceExprRefs(r *configs.Resource) []*addrs.Reference {
+	var refs []*addrs.Reference
+	if r.Count != nil {
+		refs = append(refs, lang.ReferencesInExpr(r.Count)...)
+	}
+	if r.ForEach != nil {
+		refs = append(refs, lang.ReferencesInExpr(r.ForEach)...)
+	}
+	refs = append(refs, referencesInBody(r.Config)...)
+	return refs
+}
+
+// referencesInBody returns the references found in every attribute of the
+// given body, recursing into nested blocks. This intentionally does not
+// require a schema, so it only supports the native HCL syntax; bodies
+// loaded from JSON configuration are not currently analyzed and yield no
+// references.
+func referencesInBody(body hcl.Body) []*addrs.Reference {
+	b, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	var refs []*addrs.Reference
+	for _, attr := range b.Attributes {
+		refs = append(refs, lang.ReferencesInExpr(attr.Expr)...)
+	}
+	for _, block := range b.Blocks {
+		refs = append(refs, referencesInBody(block.Body)...)
+	}
+	return refs
+}
+
+// moduleCallArgRefs returns, for each input variable name the module call
+// sets, the references found in the expression assigned to it. Like
+// referencesInBody, this only supports the native HCL syntax.
+func moduleCallArgRefs(mc *configs.ModuleCall) map[string][]*addrs.Reference {
+	ret := make(map[string][]*addrs.Reference)
+	b, ok := mc.Config.(*hclsyntax.Body)
+	if !ok {
+		return ret
+	}
+	for name, attr := range b.Attributes {
+		ret[name] = lang.ReferencesInExpr(attr.Expr)
+	}
+	return ret
+}
+
+// crossModuleReferent translates a reference Subject that points at a
+// module call's output into the output value it corresponds to in the
+// called module, so that graph traversal can step across the module
+// boundary. Any other kind of Subject is left unchanged, attributed to
+// exprModule, the module whose configuration contains the expression.
+func crossModuleReferent(exprModule addrs.Module, subject addrs.Referenceable) (addrs.Module, addrs.Referenceable) {
+	switch s := subject.(type) {
+	case addrs.ModuleCallOutput:
+		return exprModule.Child(s.Call.Name), addrs.OutputValue{Name: s.Name}
+	case addrs.ModuleCallInstanceOutput:
+		return exprModule.Child(s.Call.Call.Name), addrs.OutputValue{Name: s.Name}
+	default:
+		return exprModule, subject
+	}
+}
@@ -0,0 +1,85 @@
+package globalref
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+)
+
+// TestModuleReferencesModuleCallGating verifies that a module call's
+// count/for_each expression is treated as contributing to every input
+// variable the called module declares, even though it's never set as an
+// explicit argument. This is the gap moduleCallGateRefs was added to close.
+func TestModuleReferencesModuleCallGating(t *testing.T) {
+	countExpr, diags := hclsyntax.ParseExpression([]byte("var.n"), "test.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse count expression: %s", diags)
+	}
+
+	root := addrs.RootModule
+	childPath := root.Child("child")
+
+	rootModule := &configs.Module{
+		ModuleCalls: map[string]*configs.ModuleCall{
+			"child": {
+				Name:  "child",
+				Count: countExpr,
+			},
+		},
+	}
+	children := map[string]*configs.Config{
+		"child": {
+			Path: childPath,
+			Module: &configs.Module{
+				Variables: map[string]*configs.Variable{
+					"x": {},
+				},
+			},
+		},
+	}
+
+	edges := moduleReferences(root, rootModule, children)
+
+	var found bool
+	for _, e := range edges {
+		referrer, ok := e.Referrer.(addrs.InputVariable)
+		if !ok || referrer.Name != "x" || !e.ReferrerModule.Equal(childPath) {
+			continue
+		}
+		if referent, ok := e.Referent.(addrs.InputVariable); ok && referent.Name == "n" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected an edge from the child module's \"x\" variable to the count expression's \"var.n\" reference, got %#v", edges)
+	}
+}
+
+// TestModuleReferencesModuleCallGatingNoChild verifies that gating
+// references are skipped gracefully when the module call's own
+// configuration is missing (e.g. an invalid call that couldn't be
+// expanded), rather than panicking on a nil child.
+func TestModuleReferencesModuleCallGatingNoChild(t *testing.T) {
+	countExpr, diags := hclsyntax.ParseExpression([]byte("var.n"), "test.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse count expression: %s", diags)
+	}
+
+	rootModule := &configs.Module{
+		ModuleCalls: map[string]*configs.ModuleCall{
+			"child": {
+				Name:  "child",
+				Count: countExpr,
+			},
+		},
+	}
+
+	edges := moduleReferences(addrs.RootModule, rootModule, nil)
+	if len(edges) != 0 {
+		t.Fatalf("expected no edges when the module call has no corresponding child config, got %#v", edges)
+	}
+}
@@ -0,0 +1,110 @@
+package globalref
+
+import (
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// Reference represents a single edge in a ReferenceGraph: the Referrer's
+// configuration contains an expression that refers to the Referent.
+type Reference struct {
+	// Referrer is the address, within ReferrerModule, of the object whose
+	// configuration contains the reference.
+	Referrer addrs.Referenceable
+
+	// ReferrerModule is the static module path containing Referrer.
+	ReferrerModule addrs.Module
+
+	// Referent is the address, within ReferentModule, of the object being
+	// referred to.
+	Referent addrs.Referenceable
+
+	// ReferentModule is the static module path containing Referent. This
+	// can differ from ReferrerModule when the reference crosses a module
+	// boundary, which happens for module call input variables (the
+	// expression lives in the parent module but the variable it populates
+	// belongs to the child) and for references to module call outputs
+	// (the reverse).
+	ReferentModule addrs.Module
+}
+
+func refKey(module addrs.Module, addr addrs.Referenceable) string {
+	return module.String() + "#" + addr.String()
+}
+
+// ReferenceGraph is a directed graph of the references present in a
+// configuration, indexed in both directions so that it can answer
+// queries about either a reference's contributors (the things it refers
+// to, transitively) or its dependents (the things that refer to it,
+// transitively).
+//
+// Construct a ReferenceGraph indirectly via Analyzer.ReferenceGraph; the
+// zero value of this type is not useful on its own.
+type ReferenceGraph struct {
+	edges []Reference
+
+	// byReferrer and byReferent both index the same edges above, keyed by
+	// refKey of the referrer and referent (respectively) of each edge, so
+	// that traversal in either direction avoids a linear scan.
+	byReferrer map[string][]Reference
+	byReferent map[string][]Reference
+}
+
+func newReferenceGraph(edges []Reference) *ReferenceGraph {
+	g := &ReferenceGraph{
+		edges:      edges,
+		byReferrer: make(map[string][]Reference, len(edges)),
+		byReferent: make(map[string][]Reference, len(edges)),
+	}
+	for _, edge := range edges {
+		fromKey := refKey(edge.ReferrerModule, edge.Referrer)
+		toKey := refKey(edge.ReferentModule, edge.Referent)
+		g.byReferrer[fromKey] = append(g.byReferrer[fromKey], edge)
+		g.byReferent[toKey] = append(g.byReferent[toKey], edge)
+	}
+	return g
+}
+
+// contributors returns the transitive set of references that the object at
+// the given key depends on, by following edges from referrer to referent.
+func (g *ReferenceGraph) contributors(module addrs.Module, addr addrs.Referenceable) []Reference {
+	visited := make(map[string]bool)
+	var ret []Reference
+
+	var visit func(module addrs.Module, addr addrs.Referenceable)
+	visit = func(module addrs.Module, addr addrs.Referenceable) {
+		key := refKey(module, addr)
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, edge := range g.byReferrer[key] {
+			ret = append(ret, edge)
+			visit(edge.ReferentModule, edge.Referent)
+		}
+	}
+	visit(module, addr)
+	return ret
+}
+
+// dependents returns the transitive set of references that depend on the
+// object at the given key, by following edges from referent back to
+// referrer.
+func (g *ReferenceGraph) dependents(module addrs.Module, addr addrs.Referenceable) []Reference {
+	visited := make(map[string]bool)
+	var ret []Reference
+
+	var visit func(module addrs.Module, addr addrs.Referenceable)
+	visit = func(module addrs.Module, addr addrs.Referenceable) {
+		key := refKey(module, addr)
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, edge := range g.byReferent[key] {
+			ret = append(ret, edge)
+			visit(edge.ReferrerModule, edge.Referrer)
+		}
+	}
+	visit(module, addr)
+	return ret
+}
@@ -0,0 +1,71 @@
+package globalref
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// TestReferenceGraphTraversal exercises contributors/dependents directly
+// against a small hand-built graph, standing in for a module with:
+//
+//	local.a -> aws_instance.src
+//	local.b -> local.a
+//	output "out" -> local.b
+//	aws_instance.dst -> local.b (e.g. via an attribute that reads local.b)
+func TestReferenceGraphTraversal(t *testing.T) {
+	root := addrs.RootModule
+	src := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "src"}
+	dst := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "dst"}
+	localA := addrs.LocalValue{Name: "a"}
+	localB := addrs.LocalValue{Name: "b"}
+	out := addrs.OutputValue{Name: "out"}
+
+	graph := newReferenceGraph([]Reference{
+		{Referrer: localA, ReferrerModule: root, Referent: src, ReferentModule: root},
+		{Referrer: localB, ReferrerModule: root, Referent: localA, ReferentModule: root},
+		{Referrer: out, ReferrerModule: root, Referent: localB, ReferentModule: root},
+		{Referrer: dst, ReferrerModule: root, Referent: localB, ReferentModule: root},
+	})
+
+	t.Run("contributors of output reach the source resource transitively", func(t *testing.T) {
+		got := graph.contributors(root, out)
+		if !containsReferent(got, root, src) {
+			t.Errorf("expected contributors of %s to include %s, got %#v", out, src, got)
+		}
+	})
+
+	t.Run("dependents of the source resource reach the output and the other resource", func(t *testing.T) {
+		got := graph.dependents(root, src)
+		if !containsReferrer(got, root, out) {
+			t.Errorf("expected dependents of %s to include %s, got %#v", src, out, got)
+		}
+		if !containsReferrer(got, root, dst) {
+			t.Errorf("expected dependents of %s to include %s, got %#v", src, dst, got)
+		}
+	})
+
+	t.Run("a resource nothing refers to has no dependents", func(t *testing.T) {
+		if got := graph.dependents(root, dst); len(got) != 0 {
+			t.Errorf("expected no dependents of %s, got %#v", dst, got)
+		}
+	})
+}
+
+func containsReferent(edges []Reference, module addrs.Module, addr addrs.Referenceable) bool {
+	for _, e := range edges {
+		if e.ReferentModule.String() == module.String() && e.Referent == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func containsReferrer(edges []Reference, module addrs.Module, addr addrs.Referenceable) bool {
+	for _, e := range edges {
+		if e.ReferrerModule.String() == module.String() && e.Referrer == addr {
+			return true
+		}
+	}
+	return false
+}
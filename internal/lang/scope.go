@@ -0,0 +1,47 @@
+package lang
+
+import (
+	"github.com/hashicorp/terraform/internal/lang/globalref"
+)
+
+// Scope is the evaluation context produced by Context.Eval, used to resolve
+// expressions against the root module.
+//
+// This type is intentionally minimal: the only capability it currently
+// exposes beyond expression evaluation itself is GlobalReferences, for
+// callers such as "terraform console" that also want to ask how the objects
+// in the configuration relate to one another.
+type Scope struct {
+	// globalRefs is populated by AttachGlobalReferences. It's nil for any
+	// Scope that wasn't constructed that way, in which case
+	// GlobalReferences returns nil.
+	globalRefs *globalref.Analyzer
+}
+
+// GlobalReferences returns an analyzer that can be used alongside this scope
+// to ask how the objects in its configuration relate to one another, e.g.
+// "which resources contribute to this expression?" or "which resources
+// would be affected if this attribute changes?".
+//
+// It returns nil if the scope wasn't produced by Context.Eval, since that's
+// currently the only thing that attaches one.
+func (s *Scope) GlobalReferences() *globalref.Analyzer {
+	if s == nil {
+		return nil
+	}
+	return s.globalRefs
+}
+
+// AttachGlobalReferences returns a shallow copy of s with its
+// GlobalReferences accessor wired up to the given analyzer. Context.Eval
+// uses this to make configuration-wide reference analysis available from
+// the scope it returns, without requiring every other Scope constructor to
+// thread through an analyzer it usually doesn't have.
+func AttachGlobalReferences(s *Scope, analyzer *globalref.Analyzer) *Scope {
+	if s == nil {
+		return nil
+	}
+	attached := *s
+	attached.globalRefs = analyzer
+	return &attached
+}
@@ -0,0 +1,15 @@
+package plans
+
+// Action describes the type of action planned for a particular resource
+// instance.
+type Action string
+
+const (
+	NoOp             Action = "no-op"
+	Create           Action = "create"
+	Read             Action = "read"
+	Update           Action = "update"
+	Delete           Action = "delete"
+	DeleteThenCreate Action = "delete-then-create"
+	CreateThenDelete Action = "create-then-delete"
+)
@@ -0,0 +1,65 @@
+package plans
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/states"
+)
+
+// Changes describes a set of proposed changes, gathered as the result of a
+// plan walk.
+type Changes struct {
+	Resources []*ResourceInstanceChange
+}
+
+// NewChanges returns a new, empty Changes ready to be populated during a
+// plan walk.
+func NewChanges() *Changes {
+	return &Changes{}
+}
+
+// SyncWrapper wraps the receiver in a ChangesSync, so that it can be safely
+// appended to from the various concurrently-executing graph nodes in a plan
+// walk.
+func (c *Changes) SyncWrapper() *ChangesSync {
+	return &ChangesSync{changes: c}
+}
+
+// ChangesSync is a concurrency-safe wrapper around a Changes, used during a
+// plan walk.
+type ChangesSync struct {
+	mu      sync.Mutex
+	changes *Changes
+}
+
+// AppendResourceInstanceChange records a single resource instance change,
+// safe for concurrent use by multiple graph nodes.
+func (cs *ChangesSync) AppendResourceInstanceChange(rc *ResourceInstanceChange) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.changes.Resources = append(cs.changes.Resources, rc)
+}
+
+// ResourceInstanceChange describes a proposed change to a single resource
+// instance.
+type ResourceInstanceChange struct {
+	// Addr is the absolute address of the resource instance this change
+	// will apply to.
+	Addr addrs.AbsResourceInstance
+
+	// DeposedKey is the identifier for a deposed object associated with
+	// Addr, or states.NotDeposed if this change applies to the instance's
+	// current object.
+	DeposedKey states.DeposedKey
+
+	// Action is the type of change being made.
+	Action Action
+
+	// ImpactedBy records the resource instances whose own changes (via
+	// -replace or -target) are the reason this instance's value could be
+	// affected, as computed by Context.Plan when PlanOpts.AnalyzeImpact is
+	// set. It's nil whenever that analysis wasn't requested, or when
+	// nothing in particular is responsible for this instance's change.
+	ImpactedBy []addrs.AbsResourceInstance
+}
@@ -0,0 +1,29 @@
+package plans
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// DynamicValue is an encoded form of a cty.Value that retains enough type
+// information to be decoded back into an equivalent value later, used for
+// storing variable values and planned object values in a plan.
+type DynamicValue []byte
+
+// NewDynamicValue encodes the given value, using the given type as the
+// implied type when decoding later. The given type must be the value's own
+// type or a suitable supertype of it (typically cty.DynamicPseudoType, so
+// that the exact type is also recorded alongside the value).
+func NewDynamicValue(val cty.Value, ty cty.Type) (DynamicValue, error) {
+	raw, err := ctyjson.Marshal(val, ty)
+	if err != nil {
+		return nil, err
+	}
+	return DynamicValue(raw), nil
+}
+
+// Decode reconstructs the value previously encoded with NewDynamicValue,
+// interpreting it against the given type.
+func (v DynamicValue) Decode(ty cty.Type) (cty.Value, error) {
+	return ctyjson.Unmarshal([]byte(v), ty)
+}
@@ -0,0 +1,25 @@
+package plans
+
+// Mode represents the various planning modes supported by Context.Plan,
+// each of which produces a plan with different semantics.
+type Mode string
+
+const (
+	// NormalMode is the default planning mode, which decides an appropriate
+	// action for each resource instance based on comparing the current
+	// state with the given configuration.
+	NormalMode Mode = "normal"
+
+	// RefreshOnlyMode is a planning mode that only refreshes the state of
+	// existing objects, never proposing any resource instance changes.
+	RefreshOnlyMode Mode = "refresh-only"
+
+	// DestroyMode is a planning mode that proposes to destroy all of the
+	// remote objects already tracked in state, without regard to what the
+	// current configuration says about them.
+	DestroyMode Mode = "destroy"
+)
+
+func (m Mode) String() string {
+	return string(m)
+}
@@ -0,0 +1,28 @@
+package plans
+
+import (
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/lang/globalref"
+	"github.com/hashicorp/terraform/internal/states"
+)
+
+// Plan is the top-level type representing the result of a call to
+// Context.Plan, describing the set of changes Terraform Core has decided
+// are necessary in order to move to the desired state.
+type Plan struct {
+	UIMode       Mode
+	Changes      *Changes
+	PriorState   *states.State
+	PrevRunState *states.State
+
+	VariableValues  map[string]DynamicValue
+	TargetAddrs     []addrs.Targetable
+	ProviderSHA256s map[string][]byte
+
+	// ReferenceGraph is the static reference graph of the configuration
+	// this plan was built from, as produced by the globalref package. It
+	// lets callers such as "terraform console" and other tooling relate
+	// the resources, variables, locals, and outputs in the configuration
+	// to one another without needing to re-derive that themselves.
+	ReferenceGraph *globalref.ReferenceGraph
+}
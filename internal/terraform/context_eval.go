@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/lang"
+	"github.com/hashicorp/terraform/internal/lang/globalref"
 	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 )
@@ -77,5 +78,14 @@ func (c *Context) Eval(config *configs.Config, state *states.State, moduleAddr a
 	// caches its contexts, so we should get hold of the context that was
 	// previously used for evaluation here, unless we skipped walking.
 	evalCtx := walker.EnterPath(moduleAddr)
-	return evalCtx.EvaluationScope(nil, EvalDataForNoInstanceKey), diags
+	scope := evalCtx.EvaluationScope(nil, EvalDataForNoInstanceKey)
+
+	// Attach a global reference analyzer to the scope so that callers such
+	// as "terraform console" can also ask how the objects in config relate
+	// to one another, e.g. via scope.GlobalReferences().ContributingResources.
+	// This needs no graph walk or state of its own, since it only concerns
+	// itself with the static shape of the configuration.
+	scope = lang.AttachGlobalReferences(scope, globalref.NewAnalyzer(config))
+
+	return scope, diags
 }
@@ -3,12 +3,15 @@ package terraform
 import (
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/instances"
+	"github.com/hashicorp/terraform/internal/lang/globalref"
 	"github.com/hashicorp/terraform/internal/plans"
 	"github.com/hashicorp/terraform/internal/refactoring"
 	"github.com/hashicorp/terraform/internal/states"
@@ -23,6 +26,38 @@ type PlanOpts struct {
 	SetVariables InputValues
 	Targets      []addrs.Targetable
 	ForceReplace []addrs.AbsResourceInstance
+
+	// AnalyzeImpact, if set, asks Terraform Core to use the configuration's
+	// global reference graph (see the globalref package) to work out which
+	// other resource instances could be affected by the Targets and
+	// ForceReplace addresses, and to annotate each planned change with the
+	// result as ImpactedBy. This lets the caller preview the blast radius of
+	// a targeted apply or a forced replacement before actually applying it.
+	//
+	// This is a read-only analysis step and never changes what the plan
+	// actually does; it only adds extra information to the result.
+	AnalyzeImpact bool
+
+	// Progress, if non-nil, receives a PlanProgressEvent for each milestone
+	// the plan walk passes through, so that a CLI or CI wrapper can render
+	// incremental progress for plans large enough that otherwise nothing
+	// would be visible until the whole walk finishes.
+	//
+	// The existing Hook interface doesn't cover this well: it's oriented
+	// around individual provider operations, and has no vocabulary for
+	// moves, postconditions, or refresh-only mode. Progress is deliberately
+	// a separate, much narrower mechanism.
+	//
+	// This is a partial implementation: today it only reports
+	// RefreshStarted/Finished (bracketing the walk as a whole) and
+	// MoveApplied. Per-resource-instance milestones as the walk decides on
+	// and evaluates each planned change are not yet reported, because that
+	// requires each resource-instance graph node to call
+	// PlanOpts.reportProgress itself, which none of them currently do; see
+	// PlanProgressEventType.
+	//
+	// Sends on this channel never block the walk; see reportProgress.
+	Progress chan<- PlanProgressEvent
 }
 
 // Plan generates an execution plan for the given context, and returns the
@@ -265,10 +300,12 @@ func (c *Context) destroyPlan(config *configs.Config, prevRunState *states.State
 	return destroyPlan, diags
 }
 
-func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState *states.State, targets []addrs.Targetable) ([]refactoring.MoveStatement, map[addrs.UniqueKey]refactoring.MoveResult) {
+func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState *states.State, targets []addrs.Targetable) ([]refactoring.MoveStatement, map[addrs.UniqueKey]refactoring.MoveResult, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
 	moveStmts := refactoring.FindMoveStatements(config)
 	moveResults := refactoring.ApplyMoves(moveStmts, prevRunState)
 	if len(targets) > 0 {
+		var missing []string
 		for _, result := range moveResults {
 			matchesTarget := false
 			for _, targetAddr := range targets {
@@ -278,12 +315,31 @@ func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState
 				}
 			}
 			if !matchesTarget {
-				// TODO: Return an error stating that a targeted plan is
-				// only valid if it includes this address that was moved.
+				missing = append(missing, fmt.Sprintf("%s (moved to %s)", result.From, result.To))
+			}
+			toMatchesTarget := false
+			for _, targetAddr := range targets {
+				if targetAddr.TargetContains(result.To) {
+					toMatchesTarget = true
+					break
+				}
+			}
+			if !toMatchesTarget {
+				missing = append(missing, fmt.Sprintf("%s (moved from %s)", result.To, result.From))
 			}
 		}
+		if len(missing) > 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Moved resource instances excluded by targeting",
+				fmt.Sprintf(
+					"Resource instances in the configuration have moved to new addresses, and these old or new addresses are not included in the current -target options: %s.\n\nTo create a valid plan, either remove the -target options to include all objects, or add these addresses to the -target options.",
+					strings.Join(missing, ", "),
+				),
+			))
+		}
 	}
-	return moveStmts, moveResults
+	return moveStmts, moveResults, diags
 }
 
 func (c *Context) postPlanValidateMoves(config *configs.Config, stmts []refactoring.MoveStatement, allInsts instances.Set) tfdiags.Diagnostics {
@@ -312,6 +368,7 @@ type planWalkOpts struct {
 func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, rootVariables InputValues, opts *PlanOpts) (*plans.Plan, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	log.Printf("[DEBUG] Building and walking plan graph for %s", opts.Mode)
+	start := time.Now()
 
 	schemas, moreDiags := c.Schemas(config, prevRunState)
 	diags = diags.Append(moreDiags)
@@ -319,7 +376,19 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, r
 		return nil, diags
 	}
 
-	moveStmts, moveResults := c.prePlanFindAndApplyMoves(config, prevRunState, opts.Targets)
+	moveStmts, moveResults, moveDiags := c.prePlanFindAndApplyMoves(config, prevRunState, opts.Targets)
+	diags = diags.Append(moveDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	for _, result := range moveResults {
+		opts.reportProgress(PlanProgressEvent{
+			Type:      PlanProgressMoveApplied,
+			Addr:      result.To,
+			MovedFrom: result.From,
+			Elapsed:   time.Since(start),
+		})
+	}
 
 	var graph *Graph
 	switch mode := opts.Mode; mode {
@@ -366,24 +435,128 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, r
 
 	// If we get here then we should definitely have a non-nil "graph", which
 	// we can now walk.
+	//
+	// Refresh and planning happen together as part of this single walk, with
+	// individual graph nodes deciding for themselves whether to refresh
+	// their associated object, so RefreshStarted/RefreshFinished below can
+	// only bracket the walk as a whole rather than pinpoint exactly when
+	// refreshing begins and ends for any one resource instance. Per-resource
+	// planning progress isn't reported yet; see PlanProgressEventType.
 	changes := plans.NewChanges()
+	if !opts.SkipRefresh {
+		opts.reportProgress(PlanProgressEvent{Type: PlanProgressRefreshStarted, Elapsed: time.Since(start)})
+	}
 	walker, walkDiags := c.walk(graph, walkPlan, &graphWalkOpts{
 		InputState:         prevRunState,
 		Changes:            changes,
 		MoveResults:        moveResults,
 		RootVariableValues: rootVariables,
+		PlanOpts:           opts,
+		PlanStart:          start,
 	})
+	if !opts.SkipRefresh {
+		opts.reportProgress(PlanProgressEvent{Type: PlanProgressRefreshFinished, Elapsed: time.Since(start)})
+	}
 	diags = diags.Append(walker.NonFatalDiagnostics)
 	diags = diags.Append(walkDiags)
 	diags = diags.Append(c.postPlanValidateMoves(config, moveStmts, walker.InstanceExpander.AllInstances()))
 
+	analyzer := globalref.NewAnalyzer(config)
+
 	plan := &plans.Plan{
 		UIMode:       opts.Mode,
 		Changes:      changes,
 		PriorState:   walker.RefreshState.Close(),
 		PrevRunState: walker.PrevRunState.Close(),
 
+		// ReferenceGraph lets downstream tooling (and terraform console)
+		// relate the resources, variables, locals, and outputs in config to
+		// one another without needing to re-derive that from the raw
+		// configuration themselves.
+		ReferenceGraph: analyzer.ReferenceGraph(),
+
 		// Other fields get populated by Context.Plan after we return
 	}
+
+	if opts.AnalyzeImpact {
+		annotateImpactedResources(analyzer, changes, opts.Targets, opts.ForceReplace)
+	}
+
 	return plan, diags
 }
+
+// annotateImpactedResources uses the configuration's reference graph to work
+// out which other resource instances could be affected by the changes the
+// caller is forcing through -target or -replace, and records the result on
+// each affected plans.ResourceInstanceChange as ImpactedBy.
+//
+// This is purely informational: it doesn't change which changes are
+// planned, only how they're explained to the caller.
+func annotateImpactedResources(analyzer *globalref.Analyzer, changes *plans.Changes, targets []addrs.Targetable, forceReplace []addrs.AbsResourceInstance) {
+	triggers := resolveImpactTriggers(changes, targets, forceReplace)
+	if len(triggers) == 0 {
+		return
+	}
+
+	// For each trigger we work out its own dependents and only attribute
+	// the impact to that trigger, rather than pooling every trigger's
+	// dependents together and crediting all of them for each impacted
+	// instance: a change to one resource shouldn't be blamed on a -target
+	// or -replace address that had nothing to do with it.
+	impactedBy := make(map[string]map[string]addrs.AbsResourceInstance)
+	for _, trigger := range triggers {
+		// The reference graph is resource-level, not instance-level: a
+		// reference to aws_instance.foo[each.key] (or any other non-literal
+		// index) can only ever resolve to the unindexed addrs.Resource, so
+		// querying with the indexed instance here would silently miss every
+		// real dependent.
+		for _, dep := range analyzer.DependentResources(trigger.Resource.Resource) {
+			for _, rc := range changes.Resources {
+				if dep.Resource != rc.Addr.Resource.Resource || !dep.Module.Equal(rc.Addr.Module) {
+					continue
+				}
+				rcKey := rc.Addr.String()
+				if impactedBy[rcKey] == nil {
+					impactedBy[rcKey] = make(map[string]addrs.AbsResourceInstance)
+				}
+				impactedBy[rcKey][trigger.String()] = trigger
+			}
+		}
+	}
+
+	for _, rc := range changes.Resources {
+		byTrigger := impactedBy[rc.Addr.String()]
+		if len(byTrigger) == 0 {
+			continue
+		}
+		list := make([]addrs.AbsResourceInstance, 0, len(byTrigger))
+		for _, trigger := range byTrigger {
+			list = append(list, trigger)
+		}
+		rc.ImpactedBy = list
+	}
+}
+
+// resolveImpactTriggers expands ForceReplace and Targets into the concrete
+// resource instances that should be treated as the root cause of any impact
+// analysis. ForceReplace addresses are already concrete instances; Targets
+// may instead be whole, unindexed resources (e.g. "-target=aws_instance.foo"
+// with no count/for_each key), in which case we resolve them against the
+// instances actually present in changes.
+func resolveImpactTriggers(changes *plans.Changes, targets []addrs.Targetable, forceReplace []addrs.AbsResourceInstance) []addrs.AbsResourceInstance {
+	triggers := make([]addrs.AbsResourceInstance, 0, len(forceReplace)+len(targets))
+	triggers = append(triggers, forceReplace...)
+	for _, t := range targets {
+		switch addr := t.(type) {
+		case addrs.AbsResourceInstance:
+			triggers = append(triggers, addr)
+		case addrs.AbsResource:
+			for _, rc := range changes.Resources {
+				if rc.Addr.Resource.Resource == addr.Resource && rc.Addr.Module.Equal(addr.Module) {
+					triggers = append(triggers, rc.Addr)
+				}
+			}
+		}
+	}
+	return triggers
+}
@@ -0,0 +1,118 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/lang/globalref"
+	"github.com/hashicorp/terraform/internal/plans"
+)
+
+func TestResolveImpactTriggers(t *testing.T) {
+	foo := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "foo"}
+	bar := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "bar"}
+	baz := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "baz"}
+
+	fooInstance := foo.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	barZero := bar.Instance(addrs.IntKey(0)).Absolute(addrs.RootModuleInstance)
+	barOne := bar.Instance(addrs.IntKey(1)).Absolute(addrs.RootModuleInstance)
+	bazInstance := baz.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	changes := &plans.Changes{
+		Resources: []*plans.ResourceInstanceChange{
+			{Addr: barZero, Action: plans.Update},
+			{Addr: barOne, Action: plans.Update},
+			{Addr: bazInstance, Action: plans.Update},
+		},
+	}
+
+	t.Run("force-replace addresses pass through unchanged", func(t *testing.T) {
+		got := resolveImpactTriggers(changes, nil, []addrs.AbsResourceInstance{fooInstance})
+		if len(got) != 1 || got[0] != fooInstance {
+			t.Fatalf("expected [%s], got %#v", fooInstance, got)
+		}
+	})
+
+	t.Run("an already-indexed target passes through unchanged", func(t *testing.T) {
+		got := resolveImpactTriggers(changes, []addrs.Targetable{barZero}, nil)
+		if len(got) != 1 || got[0] != barZero {
+			t.Fatalf("expected [%s], got %#v", barZero, got)
+		}
+	})
+
+	t.Run("an unindexed target expands to every matching instance in changes", func(t *testing.T) {
+		unindexed := bar.Absolute(addrs.RootModuleInstance)
+		got := resolveImpactTriggers(changes, []addrs.Targetable{unindexed}, nil)
+		if len(got) != 2 {
+			t.Fatalf("expected 2 triggers for the unindexed target, got %#v", got)
+		}
+	})
+}
+
+// TestAnnotateImpactedResourcesForEach exercises annotateImpactedResources
+// against a real globalref.Analyzer, for a resource that uses for_each. This
+// is the common case that querying the reference graph with an indexed
+// addrs.AbsResourceInstance (rather than the unindexed addrs.Resource it
+// actually indexes by) used to miss entirely.
+func TestAnnotateImpactedResourcesForEach(t *testing.T) {
+	bar := &configs.Resource{
+		Mode:    addrs.ManagedResourceMode,
+		Type:    "aws_instance",
+		Name:    "bar",
+		ForEach: mustParseExpr(t, "var.items"),
+	}
+	baz := &configs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "aws_instance",
+		Name: "baz",
+		Config: &hclsyntax.Body{
+			Attributes: hclsyntax.Attributes{
+				"depends_on_bar": {
+					Name: "depends_on_bar",
+					Expr: mustParseExpr(t, "aws_instance.bar"),
+				},
+			},
+		},
+	}
+	config := &configs.Config{
+		Path: addrs.RootModule,
+		Module: &configs.Module{
+			ManagedResources: map[string]*configs.Resource{
+				"aws_instance.bar": bar,
+				"aws_instance.baz": baz,
+			},
+		},
+	}
+	analyzer := globalref.NewAnalyzer(config)
+
+	barResource := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "bar"}
+	bazResource := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "baz"}
+	barInstance := barResource.Instance(addrs.StringKey("x")).Absolute(addrs.RootModuleInstance)
+	bazInstance := bazResource.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	changes := &plans.Changes{
+		Resources: []*plans.ResourceInstanceChange{
+			{Addr: bazInstance, Action: plans.Update},
+		},
+	}
+
+	annotateImpactedResources(analyzer, changes, nil, []addrs.AbsResourceInstance{barInstance})
+
+	got := changes.Resources[0].ImpactedBy
+	if len(got) != 1 || got[0] != barInstance {
+		t.Fatalf("expected aws_instance.baz's ImpactedBy to be [%s], got %#v", barInstance, got)
+	}
+}
+
+func mustParseExpr(t *testing.T, src string) hcl.Expression {
+	t.Helper()
+	expr, diags := hclsyntax.ParseExpression([]byte(src), "test.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse %q: %s", src, diags)
+	}
+	return expr
+}
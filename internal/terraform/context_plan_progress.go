@@ -0,0 +1,82 @@
+package terraform
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// PlanProgressEventType identifies the kind of milestone a PlanProgressEvent
+// is reporting. It exists mainly so that consumers can switch on it without
+// needing to inspect which of the event's other fields are populated.
+//
+// KNOWN LIMITATION: this is a partial implementation of walk-wide plan
+// progress reporting. RefreshStarted/Finished and MoveApplied are sent from
+// Context.Plan itself and so are fully implemented below, but per-resource
+// milestones (e.g. "planning has started for this instance" or "this
+// instance's change has been decided") are not: those would need to be sent
+// from the individual resource-instance graph nodes that make those
+// decisions, and none of them call PlanOpts.reportProgress today. Consumers
+// needing per-resource progress can't rely on this API for that yet.
+type PlanProgressEventType string
+
+const (
+	// PlanProgressRefreshStarted and PlanProgressRefreshFinished bracket the
+	// whole plan walk, within which existing objects are refreshed alongside
+	// planning; they do not mark when refreshing begins or ends for any one
+	// instance, and are not sent when PlanOpts.SkipRefresh is set. Addr is
+	// unset for these events, since they describe the walk as a whole rather
+	// than a single instance.
+	PlanProgressRefreshStarted  PlanProgressEventType = "RefreshStarted"
+	PlanProgressRefreshFinished PlanProgressEventType = "RefreshFinished"
+
+	// PlanProgressMoveApplied is sent for each moved resource instance, once
+	// the move has been applied to the working state that the rest of the
+	// plan walk will see.
+	PlanProgressMoveApplied PlanProgressEventType = "MoveApplied"
+)
+
+// PlanProgressEvent is a single milestone reported during a plan walk via
+// PlanOpts.Progress, intended for CLI and CI wrappers that want to render
+// incremental progress for configurations large enough that otherwise
+// nothing would be visible until the whole walk finishes.
+//
+// Events are sent in the order the walk discovers them, but because the
+// underlying graph walk runs many resource instances concurrently, events
+// for different instances can interleave arbitrarily; Elapsed should be used
+// to reconstruct timing rather than assuming events arrive in lockstep with
+// wall-clock phases.
+type PlanProgressEvent struct {
+	Type PlanProgressEventType
+
+	// Addr identifies the resource instance the event concerns. It's unset
+	// for events that describe the walk as a whole, such as
+	// PlanProgressRefreshStarted/Finished.
+	Addr addrs.AbsResourceInstance
+
+	// Elapsed is the time since the plan walk that sent the event started.
+	// A destroy plan that also refreshes runs two walks, each with its own
+	// start time.
+	Elapsed time.Duration
+
+	// MovedFrom is populated for PlanProgressMoveApplied events and holds
+	// the address the resource instance was moved from; Addr holds the
+	// address it was moved to.
+	MovedFrom addrs.AbsResourceInstance
+}
+
+// reportProgress sends ev on the configured progress channel, if any.
+//
+// The send is non-blocking: if the channel's buffer (if any) is full, or
+// nobody is currently receiving, the event is dropped rather than stalling
+// the plan walk. Callers that need a complete event log should therefore
+// provide a channel with enough buffer for their consumption pattern.
+func (o *PlanOpts) reportProgress(ev PlanProgressEvent) {
+	if o == nil || o.Progress == nil {
+		return
+	}
+	select {
+	case o.Progress <- ev:
+	default:
+	}
+}
@@ -0,0 +1,36 @@
+package terraform
+
+import "testing"
+
+func TestPlanOptsReportProgress(t *testing.T) {
+	t.Run("nil PlanOpts is a no-op", func(t *testing.T) {
+		var opts *PlanOpts
+		opts.reportProgress(PlanProgressEvent{Type: PlanProgressRefreshStarted})
+	})
+
+	t.Run("nil Progress channel is a no-op", func(t *testing.T) {
+		opts := &PlanOpts{}
+		opts.reportProgress(PlanProgressEvent{Type: PlanProgressRefreshStarted})
+	})
+
+	t.Run("a full channel doesn't block the caller", func(t *testing.T) {
+		ch := make(chan PlanProgressEvent) // unbuffered, nobody receiving
+		opts := &PlanOpts{Progress: ch}
+		opts.reportProgress(PlanProgressEvent{Type: PlanProgressRefreshStarted})
+	})
+
+	t.Run("an event is delivered into a buffered channel", func(t *testing.T) {
+		ch := make(chan PlanProgressEvent, 1)
+		opts := &PlanOpts{Progress: ch}
+		opts.reportProgress(PlanProgressEvent{Type: PlanProgressMoveApplied})
+
+		select {
+		case ev := <-ch:
+			if ev.Type != PlanProgressMoveApplied {
+				t.Errorf("got event type %q, want %q", ev.Type, PlanProgressMoveApplied)
+			}
+		default:
+			t.Fatal("expected an event to be buffered, found none")
+		}
+	})
+}
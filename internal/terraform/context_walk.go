@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/instances"
@@ -24,6 +25,14 @@ type graphWalkOpts struct {
 
 	RootVariableValues InputValues
 	MoveResults        map[addrs.UniqueKey]refactoring.MoveResult
+
+	// PlanOpts and PlanStart are only set for walkPlan (and its variants)
+	// walks, and let graph nodes report PlanProgressEvents as they make
+	// per-resource planning decisions. PlanOpts may be nil, and PlanOpts.
+	// Progress may be nil even when PlanOpts isn't; both cases mean no
+	// progress events should be sent.
+	PlanOpts  *PlanOpts
+	PlanStart time.Time
 }
 
 func (c *Context) walk(graph *Graph, operation walkOperation, opts *graphWalkOpts) (*ContextGraphWalker, tfdiags.Diagnostics) {
@@ -79,5 +88,7 @@ func (c *Context) graphWalker(operation walkOperation, opts *graphWalkOpts) *Con
 		Operation:          operation,
 		StopContext:        c.runContext,
 		RootVariableValues: opts.RootVariableValues,
+		PlanOpts:           opts.PlanOpts,
+		PlanStart:          opts.PlanStart,
 	}
 }
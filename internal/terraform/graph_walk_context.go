@@ -0,0 +1,49 @@
+package terraform
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/instances"
+	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/refactoring"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// ContextGraphWalker is the GraphWalker implementation used by Context.walk
+// to carry the transient, walk-scoped state that graph nodes need while
+// they're visited: the working state(s) being built up, the expander that
+// tracks resource-for_each/count instances, and so on.
+//
+// A ContextGraphWalker is only valid for the duration of a single graph
+// walk; callers shouldn't retain one beyond the walk method call that
+// produced it.
+type ContextGraphWalker struct {
+	Context      *Context
+	State        *states.SyncState
+	RefreshState *states.SyncState
+	PrevRunState *states.SyncState
+	Changes      *plans.ChangesSync
+
+	InstanceExpander *instances.Expander
+	MoveResults      map[addrs.UniqueKey]refactoring.MoveResult
+
+	Operation          walkOperation
+	StopContext        context.Context
+	RootVariableValues InputValues
+
+	// PlanOpts and PlanStart carry the planning options and start time
+	// through to graph nodes during a plan walk, so that they can report
+	// PlanProgressEvents via PlanOpts.reportProgress as they make
+	// per-resource planning decisions. Both are zero-valued outside of
+	// walkPlan and walkPlanDestroy walks.
+	PlanOpts  *PlanOpts
+	PlanStart time.Time
+
+	// NonFatalDiagnostics accumulates diagnostics raised by graph nodes
+	// that don't halt the walk, for the caller to fold into its own
+	// returned diagnostics once the walk completes.
+	NonFatalDiagnostics tfdiags.Diagnostics
+}